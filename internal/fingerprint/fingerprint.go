@@ -0,0 +1,216 @@
+// Package fingerprint loads a YAML ruleset describing how to recognize
+// account/login portals and the identity products that back them, and
+// matches that ruleset against probed HTTP responses.
+package fingerprint
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Category groups rules by the kind of portal they detect.
+type Category string
+
+const (
+	CategorySSO           Category = "sso"
+	CategoryOAuth         Category = "oauth"
+	CategoryMFA           Category = "mfa"
+	CategoryPasswordReset Category = "password-reset"
+	CategoryGenericLogin  Category = "generic-login"
+)
+
+// Rule is a single fingerprint definition as it appears in the YAML config.
+type Rule struct {
+	Name              string            `yaml:"name"`
+	Category          Category          `yaml:"category"`
+	Paths             []string          `yaml:"paths"`
+	BodyRegexes       []string          `yaml:"body_regexes"`
+	HeaderRegexes     map[string]string `yaml:"header_regexes"`
+	FormActionRegexes []string          `yaml:"form_action_regexes"`
+	Technologies      []string          `yaml:"technologies"`
+
+	bodyRegexes       []*regexp.Regexp
+	headerRegexes     map[string]*regexp.Regexp
+	formActionRegexes []*regexp.Regexp
+}
+
+// Config is a loaded, compiled ruleset.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Match describes a single rule that matched a probed response.
+type Match struct {
+	Rule           string
+	Category       Category
+	Technologies   []string
+	MatchedKeyword string
+	MatchedHeader  string
+	HeaderName     string
+	FormAction     string
+}
+
+// LoadConfig reads and compiles a fingerprint ruleset from a YAML file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fingerprint config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing fingerprint config: %w", err)
+	}
+
+	if err := cfg.compile(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) compile() error {
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+
+		if rule.Category == "" {
+			rule.Category = CategoryGenericLogin
+		}
+
+		for _, pattern := range rule.BodyRegexes {
+			re, err := regexp.Compile(`(?i)` + pattern)
+			if err != nil {
+				return fmt.Errorf("rule %q: compiling body regex %q: %w", rule.Name, pattern, err)
+			}
+			rule.bodyRegexes = append(rule.bodyRegexes, re)
+		}
+
+		if len(rule.HeaderRegexes) > 0 {
+			rule.headerRegexes = make(map[string]*regexp.Regexp, len(rule.HeaderRegexes))
+			for header, pattern := range rule.HeaderRegexes {
+				re, err := regexp.Compile(`(?i)` + pattern)
+				if err != nil {
+					return fmt.Errorf("rule %q: compiling header regex %q: %w", rule.Name, pattern, err)
+				}
+				rule.headerRegexes[header] = re
+			}
+		}
+
+		for _, pattern := range rule.FormActionRegexes {
+			re, err := regexp.Compile(`(?i)` + pattern)
+			if err != nil {
+				return fmt.Errorf("rule %q: compiling form action regex %q: %w", rule.Name, pattern, err)
+			}
+			rule.formActionRegexes = append(rule.formActionRegexes, re)
+		}
+	}
+
+	return nil
+}
+
+// Paths returns the de-duplicated union of every rule's probe paths, in the
+// order they first appear.
+func (c *Config) Paths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, rule := range c.Rules {
+		for _, path := range rule.Paths {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// Evaluate checks a probed response body, headers, and (if any) form action
+// against every rule and returns the ones that matched.
+func (c *Config) Evaluate(body string, headers http.Header, formAction string) []Match {
+	var matches []Match
+
+	for _, rule := range c.Rules {
+		var m Match
+		matched := false
+
+		for _, re := range rule.bodyRegexes {
+			if loc := re.FindString(body); loc != "" {
+				m.MatchedKeyword = loc
+				matched = true
+				break
+			}
+		}
+
+		for header, re := range rule.headerRegexes {
+			if value := headers.Get(header); value != "" && re.MatchString(value) {
+				m.HeaderName = header
+				m.MatchedHeader = value
+				matched = true
+				break
+			}
+		}
+
+		if formAction != "" {
+			for _, re := range rule.formActionRegexes {
+				if re.MatchString(formAction) {
+					m.FormAction = formAction
+					matched = true
+					break
+				}
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		m.Rule = rule.Name
+		m.Category = rule.Category
+		m.Technologies = rule.Technologies
+		matches = append(matches, m)
+	}
+
+	return matches
+}
+
+// MatchesAny reports whether any rule's body regex matches the given text.
+// It is used to classify fragments (e.g. link or button text) rather than a
+// full response body.
+func (c *Config) MatchesAny(text string) bool {
+	for _, rule := range c.Rules {
+		for _, re := range rule.bodyRegexes {
+			if re.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Default returns the built-in ruleset, equivalent to portalfinder's
+// original hardcoded keyword/path detection. It is used when no -config
+// flag is given.
+func Default() *Config {
+	cfg := &Config{
+		Rules: []Rule{
+			{
+				Name:     "generic-login-form",
+				Category: CategoryGenericLogin,
+				Paths: []string{
+					"login", "register", "signup", "signin", "create-account", "log-in",
+					"sign-in", "sign-up", "authentication", "forgot-password", "reset-password",
+				},
+				BodyRegexes: []string{
+					"login", "register", "signup", "signin", "create account", "log in",
+					"sign in", "sign up", "authentication", "forgot password", "reset password",
+				},
+			},
+		},
+	}
+	_ = cfg.compile()
+	return cfg
+}
@@ -0,0 +1,126 @@
+package fingerprint
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultMatchesLoginKeyword(t *testing.T) {
+	cfg := Default()
+
+	body := `<html><body><form action="/login">Please login</form></body></html>`
+	matches := cfg.Evaluate(body, http.Header{}, "")
+	if len(matches) == 0 {
+		t.Fatalf("expected at least one match for login body, got none")
+	}
+	if matches[0].MatchedKeyword == "" {
+		t.Errorf("expected MatchedKeyword to be set")
+	}
+}
+
+func TestEvaluateHeaderRegex(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{
+				Name:     "okta",
+				Category: CategorySSO,
+				HeaderRegexes: map[string]string{
+					"WWW-Authenticate": "Okta",
+				},
+			},
+		},
+	}
+	if err := cfg.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	headers := http.Header{}
+	headers.Set("WWW-Authenticate", "Okta realm=\"example\"")
+
+	matches := cfg.Evaluate("", headers, "")
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Rule != "okta" {
+		t.Errorf("expected rule okta, got %q", matches[0].Rule)
+	}
+}
+
+func TestLoadConfigParsesYAMLFixture(t *testing.T) {
+	yaml := `
+rules:
+  - name: okta
+    category: sso
+    paths:
+      - login
+      - sso
+    body_regexes:
+      - okta-sign-in
+    header_regexes:
+      WWW-Authenticate: "Okta"
+    form_action_regexes:
+      - "\\.okta\\.com"
+    technologies:
+      - Okta
+`
+	path := filepath.Join(t.TempDir(), "fingerprint.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	paths := cfg.Paths()
+	if len(paths) != 2 || paths[0] != "login" || paths[1] != "sso" {
+		t.Fatalf("Paths() = %v, want [login sso]", paths)
+	}
+
+	headers := http.Header{}
+	headers.Set("WWW-Authenticate", "Okta realm=\"example\"")
+	matches := cfg.Evaluate("this page embeds okta-sign-in", headers, "https://tenant.okta.com/login")
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Rule != "okta" || len(matches[0].Technologies) != 1 || matches[0].Technologies[0] != "Okta" {
+		t.Errorf("unexpected match: %+v", matches[0])
+	}
+}
+
+func TestLoadConfigRejectsBadRegex(t *testing.T) {
+	yaml := `
+rules:
+  - name: broken
+    body_regexes:
+      - "("
+`
+	path := filepath.Join(t.TempDir(), "fingerprint.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	if _, err := LoadConfig(path); err == nil {
+		t.Fatalf("expected LoadConfig to reject an invalid body regex")
+	}
+}
+
+func TestPathsDeduplicated(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{Name: "a", Paths: []string{"login", "signup"}},
+			{Name: "b", Paths: []string{"login", "sso"}},
+		},
+	}
+	if err := cfg.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	paths := cfg.Paths()
+	if len(paths) != 3 {
+		t.Fatalf("expected 3 deduplicated paths, got %d (%v)", len(paths), paths)
+	}
+}
@@ -0,0 +1,155 @@
+// Package formparse extracts structured intelligence from HTML <form>
+// elements: the action/method, named input fields (flagging password,
+// email, username, OTP, and hidden CSRF-token inputs), and any OAuth
+// parameters (client_id, redirect_uri) carried in the form action's query
+// string.
+package formparse
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Field is a single <input>/<select>/<textarea> inside a form.
+type Field struct {
+	Name   string
+	Type   string
+	Value  string
+	Hidden bool
+}
+
+// Form is one parsed <form> element plus the classifications portalfinder
+// cares about for login/account portals.
+type Form struct {
+	Action       string
+	Method       string
+	Fields       []Field
+	HasPassword  bool
+	HasEmail     bool
+	HasUsername  bool
+	HasOTP       bool
+	HasCSRFToken bool
+	OAuthParams  map[string]string
+}
+
+// Parse walks the HTML document in body and returns every <form> it finds.
+func Parse(body string) ([]Form, error) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []Form
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "form" {
+			forms = append(forms, parseForm(n))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return forms, nil
+}
+
+func parseForm(n *html.Node) Form {
+	form := Form{Method: "GET"}
+
+	for _, attr := range n.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "action":
+			form.Action = attr.Val
+		case "method":
+			form.Method = strings.ToUpper(attr.Val)
+		}
+	}
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && (node.Data == "input" || node.Data == "select" || node.Data == "textarea") {
+			form.Fields = append(form.Fields, parseField(node))
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c)
+	}
+
+	classifyFields(&form)
+	form.OAuthParams = extractOAuthParams(form.Action)
+
+	return form
+}
+
+func parseField(n *html.Node) Field {
+	field := Field{Type: "text"}
+	for _, attr := range n.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "name":
+			field.Name = attr.Val
+		case "type":
+			field.Type = strings.ToLower(attr.Val)
+		case "value":
+			field.Value = attr.Val
+		}
+	}
+	field.Hidden = field.Type == "hidden"
+	return field
+}
+
+func classifyFields(form *Form) {
+	for _, field := range form.Fields {
+		name := strings.ToLower(field.Name)
+
+		switch {
+		case field.Type == "password":
+			form.HasPassword = true
+		case strings.Contains(name, "email"):
+			form.HasEmail = true
+		case strings.Contains(name, "user"):
+			form.HasUsername = true
+		case strings.Contains(name, "otp") || strings.Contains(name, "totp"):
+			form.HasOTP = true
+		}
+
+		if field.Hidden && isCSRFFieldName(name) {
+			form.HasCSRFToken = true
+		}
+	}
+}
+
+func isCSRFFieldName(name string) bool {
+	for _, hint := range []string{"csrf", "_token", "authenticity_token", "xsrf"} {
+		if strings.Contains(name, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractOAuthParams pulls client_id/redirect_uri out of the form action's
+// query string, if present.
+func extractOAuthParams(action string) map[string]string {
+	u, err := url.Parse(action)
+	if err != nil {
+		return nil
+	}
+
+	params := map[string]string{}
+	for _, key := range []string{"client_id", "redirect_uri"} {
+		if value := u.Query().Get(key); value != "" {
+			params[key] = value
+		}
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
@@ -0,0 +1,34 @@
+package formparse
+
+import "testing"
+
+func TestParseExtractsFieldsAndOAuthParams(t *testing.T) {
+	body := `
+	<html><body>
+		<form action="https://tenant.auth0.com/login?client_id=abc123&redirect_uri=https://app.example.com/callback" method="post">
+			<input type="hidden" name="csrf_token" value="xyz">
+			<input type="email" name="email">
+			<input type="password" name="password">
+			<input type="text" name="otp_code">
+		</form>
+	</body></html>`
+
+	forms, err := Parse(body)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(forms) != 1 {
+		t.Fatalf("expected 1 form, got %d", len(forms))
+	}
+
+	form := forms[0]
+	if !form.HasPassword || !form.HasEmail || !form.HasOTP || !form.HasCSRFToken {
+		t.Errorf("expected all classifications set, got %+v", form)
+	}
+	if form.OAuthParams["client_id"] != "abc123" {
+		t.Errorf("expected client_id=abc123, got %q", form.OAuthParams["client_id"])
+	}
+	if form.OAuthParams["redirect_uri"] != "https://app.example.com/callback" {
+		t.Errorf("unexpected redirect_uri: %q", form.OAuthParams["redirect_uri"])
+	}
+}
@@ -0,0 +1,110 @@
+// Package httpx builds the *http.Client used to probe subdomains, wiring
+// up proxying, TLS verification, retries, and custom/randomized headers
+// around a single properly-tuned Transport.
+package httpx
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config controls how Build constructs the client.
+type Config struct {
+	Timeout         time.Duration
+	Retries         int
+	Proxy           string
+	Headers         []string // "Key: Value" pairs applied to every request
+	RandomUserAgent bool
+	Insecure        bool
+}
+
+var userAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0",
+}
+
+// Build assembles an *http.Client per cfg. The returned client's Transport
+// is tuned for scanning many hosts: bounded idle connections per host and
+// an optional proxy/insecure TLS configuration.
+func Build(cfg Config) (*http.Client, error) {
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: 100,
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: cfg.Insecure},
+	}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	var rt http.RoundTripper = transport
+	rt = &headerRoundTripper{next: rt, headers: cfg.Headers, randomUserAgent: cfg.RandomUserAgent}
+	rt = &retryRoundTripper{next: rt, retries: cfg.Retries}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: rt,
+	}, nil
+}
+
+type headerRoundTripper struct {
+	next            http.RoundTripper
+	headers         []string
+	randomUserAgent bool
+}
+
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	for _, header := range h.headers {
+		key, value, ok := strings.Cut(header, ":")
+		if !ok {
+			continue
+		}
+		req.Header.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	if h.randomUserAgent && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", userAgents[rand.Intn(len(userAgents))])
+	}
+
+	return h.next.RoundTrip(req)
+}
+
+// retryRoundTripper retries a request up to `retries` additional times on
+// transport errors or 5xx responses.
+type retryRoundTripper struct {
+	next    http.RoundTripper
+	retries int
+}
+
+func (r *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+
+	attempts := r.retries + 1
+	for attempt := 0; attempt < attempts; attempt++ {
+		resp, err = r.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if attempt < attempts-1 {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			time.Sleep(time.Duration(attempt+1) * 200 * time.Millisecond)
+		}
+	}
+
+	return resp, err
+}
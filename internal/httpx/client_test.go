@@ -0,0 +1,168 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryRoundTripperRetriesOn5xx(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := Build(Config{Retries: 2})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryRoundTripperGivesUpAfterRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := Build(Config{Retries: 1})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d, want 503", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("attempts = %d, want 2 (retries+1)", got)
+	}
+}
+
+func TestHeaderRoundTripperSetsCustomHeaders(t *testing.T) {
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Api-Key")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := Build(Config{Headers: []string{"X-Api-Key: secret"}})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHeader != "secret" {
+		t.Errorf("X-Api-Key = %q, want secret", gotHeader)
+	}
+}
+
+func TestHeaderRoundTripperRandomUserAgentOnlyWhenUnset(t *testing.T) {
+	var gotUA string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := Build(Config{RandomUserAgent: true})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	found := false
+	for _, ua := range userAgents {
+		if ua == gotUA {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("User-Agent = %q, want one of %v", gotUA, userAgents)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("User-Agent", "explicit-agent")
+
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotUA != "explicit-agent" {
+		t.Errorf("User-Agent = %q, want explicit-agent to be preserved", gotUA)
+	}
+}
+
+func TestBuildWiresProxy(t *testing.T) {
+	client, err := Build(Config{Proxy: "http://127.0.0.1:9"})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	transport, ok := client.Transport.(*retryRoundTripper).next.(*headerRoundTripper).next.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected underlying *http.Transport")
+	}
+	if transport.Proxy == nil {
+		t.Fatalf("expected Proxy func to be set")
+	}
+}
+
+func TestBuildInvalidProxyErrors(t *testing.T) {
+	if _, err := Build(Config{Proxy: "://not-a-url"}); err == nil {
+		t.Fatalf("expected error for invalid proxy URL")
+	}
+}
+
+func TestBuildSetsTimeout(t *testing.T) {
+	client, err := Build(Config{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if client.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", client.Timeout)
+	}
+}
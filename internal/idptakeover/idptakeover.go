@@ -0,0 +1,121 @@
+// Package idptakeover looks for third-party identity provider references
+// (Auth0 tenants, Firebase projects, Okta orgs, Cognito user pools) inside
+// a login page and flags ones that resolve to NXDOMAIN or a provider
+// "not found" page - a dangling IdP tenant, subjack-style.
+package idptakeover
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// defaultTimeout bounds a single host's DNS lookup + HTTP check when the
+// caller doesn't supply one, so a black-holed third-party host can't hang
+// the worker that found it.
+const defaultTimeout = 10 * time.Second
+
+// Finding is a third-party IdP host discovered on a login page, along with
+// whether it looks to be dangling.
+type Finding struct {
+	Provider string
+	Host     string
+	Dangling bool
+	Reason   string
+}
+
+type provider struct {
+	name         string
+	hostPattern  *regexp.Regexp
+	notFoundBody *regexp.Regexp
+}
+
+var providers = []provider{
+	{
+		name:         "auth0",
+		hostPattern:  regexp.MustCompile(`[a-zA-Z0-9-]+\.auth0\.com`),
+		notFoundBody: regexp.MustCompile(`(?i)tenant not found|doesn't exist`),
+	},
+	{
+		name:         "firebase",
+		hostPattern:  regexp.MustCompile(`[a-zA-Z0-9-]+\.(firebaseapp\.com|firebaseio\.com)`),
+		notFoundBody: regexp.MustCompile(`(?i)site not found`),
+	},
+	{
+		name:         "okta",
+		hostPattern:  regexp.MustCompile(`[a-zA-Z0-9-]+\.okta(preview)?\.com`),
+		notFoundBody: regexp.MustCompile(`(?i)the page (you were looking for|you requested) (doesn't|does not) exist`),
+	},
+	{
+		name:         "cognito",
+		hostPattern:  regexp.MustCompile(`[a-zA-Z0-9-]+\.auth\.[a-zA-Z0-9-]+\.amazoncognito\.com`),
+		notFoundBody: regexp.MustCompile(`(?i)user pool.*not found|does not exist`),
+	},
+}
+
+// Check scans text (typically a login page's body plus its form action)
+// for third-party IdP hostnames and reports which of them appear dangling.
+// Each host's DNS lookup and HTTP check is bounded by timeout (falling back
+// to defaultTimeout when timeout is zero) so a slow or black-holed host
+// can't stall the calling worker indefinitely.
+func Check(ctx context.Context, client *http.Client, timeout time.Duration, text string) []Finding {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	seen := make(map[string]bool)
+	var findings []Finding
+
+	for _, p := range providers {
+		for _, host := range p.hostPattern.FindAllString(text, -1) {
+			if seen[host] {
+				continue
+			}
+			seen[host] = true
+			findings = append(findings, evaluate(ctx, client, timeout, p, host))
+		}
+	}
+
+	return findings
+}
+
+func evaluate(ctx context.Context, client *http.Client, timeout time.Duration, p provider, host string) Finding {
+	finding := Finding{Provider: p.name, Host: host}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		finding.Dangling = true
+		finding.Reason = "NXDOMAIN"
+		return finding
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+host, nil)
+	if err != nil {
+		return finding
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Resolves but unreachable isn't itself evidence of a dangling
+		// tenant, so leave Dangling false.
+		return finding
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 20*1024))
+	if err != nil {
+		return finding
+	}
+
+	if p.notFoundBody.Match(body) {
+		finding.Dangling = true
+		finding.Reason = "provider returned a not-found page"
+	}
+
+	return finding
+}
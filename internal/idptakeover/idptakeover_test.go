@@ -0,0 +1,104 @@
+package idptakeover
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProviderHostPatterns(t *testing.T) {
+	cases := []struct {
+		provider string
+		text     string
+	}{
+		{"auth0", "action=\"https://acme-corp.auth0.com/login\""},
+		{"firebase", "https://acme-corp.firebaseapp.com/__/auth/handler"},
+		{"okta", "https://acme-corp.okta.com/login"},
+		{"cognito", "https://acme-corp.auth.us-east-1.amazoncognito.com/login"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.provider, func(t *testing.T) {
+			for _, p := range providers {
+				if p.name != c.provider {
+					continue
+				}
+				if !p.hostPattern.MatchString(c.text) {
+					t.Errorf("expected %s host pattern to match %q", c.provider, c.text)
+				}
+				return
+			}
+			t.Fatalf("no provider registered for %s", c.provider)
+		})
+	}
+}
+
+// stubRoundTripper returns a canned response for every request without
+// touching the network, so the HTTP leg of evaluate can be tested in
+// isolation from DNS.
+type stubRoundTripper struct {
+	status int
+	body   string
+}
+
+func (s stubRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: s.status,
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestEvaluateDanglingOnNXDOMAIN(t *testing.T) {
+	p := providers[0]
+	client := &http.Client{Transport: stubRoundTripper{status: http.StatusOK, body: "irrelevant"}}
+
+	finding := evaluate(context.Background(), client, time.Second, p, "clearly-nonexistent-host.invalid")
+
+	if !finding.Dangling {
+		t.Errorf("expected Dangling=true for an unresolvable host")
+	}
+	if finding.Reason != "NXDOMAIN" {
+		t.Errorf("Reason = %q, want NXDOMAIN", finding.Reason)
+	}
+}
+
+func TestEvaluateDanglingOnNotFoundBody(t *testing.T) {
+	var p provider
+	for _, candidate := range providers {
+		if candidate.name == "auth0" {
+			p = candidate
+		}
+	}
+
+	client := &http.Client{Transport: stubRoundTripper{status: http.StatusOK, body: "Sorry, this tenant not found."}}
+
+	finding := evaluate(context.Background(), client, time.Second, p, "localhost")
+
+	if !finding.Dangling {
+		t.Errorf("expected Dangling=true for a not-found response body")
+	}
+	if finding.Reason != "provider returned a not-found page" {
+		t.Errorf("Reason = %q, want %q", finding.Reason, "provider returned a not-found page")
+	}
+}
+
+func TestEvaluateNotDanglingOnHealthyBody(t *testing.T) {
+	var p provider
+	for _, candidate := range providers {
+		if candidate.name == "auth0" {
+			p = candidate
+		}
+	}
+
+	client := &http.Client{Transport: stubRoundTripper{status: http.StatusOK, body: "Welcome to Acme Corp login"}}
+
+	finding := evaluate(context.Background(), client, time.Second, p, "localhost")
+
+	if finding.Dangling {
+		t.Errorf("expected Dangling=false for a healthy response body, got Reason=%q", finding.Reason)
+	}
+}
@@ -0,0 +1,200 @@
+// Package wayback discovers historical paths for a host via the Wayback
+// Machine CDX API, filters them down to auth-portal-shaped candidates, and
+// caches the result to disk so repeat runs don't re-hit the archive.
+package wayback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const cdxEndpoint = "http://web.archive.org/cdx/search/cdx"
+
+// portalKeywords filters the (often huge) set of historical URLs down to
+// the ones that look like they could be an auth portal.
+var portalKeywords = []string{
+	"login", "signin", "sign-in", "signup", "sign-up", "sso", "oauth",
+	"auth", "authenticate", "account", "portal", "saml", "cas", "adfs",
+	"idp", "identity", "mfa", "password", "session",
+}
+
+// Discoverer queries the CDX API for historical URLs and caches results
+// per-host on disk.
+type Discoverer struct {
+	Client   *http.Client
+	CacheDir string
+	TTL      time.Duration
+}
+
+// NewDiscoverer returns a Discoverer that caches under cacheDir (created if
+// it doesn't already exist). A zero TTL disables cache reuse.
+func NewDiscoverer(client *http.Client, cacheDir string, ttl time.Duration) (*Discoverer, error) {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating wayback cache dir: %w", err)
+	}
+	return &Discoverer{Client: client, CacheDir: cacheDir, TTL: ttl}, nil
+}
+
+// DefaultCacheDir returns ~/.config/portalfinder/wayback-cache.
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "portalfinder", "wayback-cache"), nil
+}
+
+// Paths returns the deduplicated, keyword-filtered set of historical paths
+// (relative to subdomain) discovered for subdomain, using the disk cache
+// when it is fresh.
+func (d *Discoverer) Paths(ctx context.Context, subdomain string) ([]string, error) {
+	host := cacheKey(subdomain)
+
+	if paths, ok := d.readCache(host); ok {
+		return paths, nil
+	}
+
+	urls, err := d.queryCDX(ctx, subdomain)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := filterAndRelativize(urls)
+
+	if err := d.writeCache(host, paths); err != nil {
+		return paths, err
+	}
+
+	return paths, nil
+}
+
+func (d *Discoverer) queryCDX(ctx context.Context, subdomain string) ([]string, error) {
+	query := url.Values{}
+	query.Set("url", strings.TrimSuffix(subdomain, "/")+"/*")
+	query.Set("output", "json")
+	query.Set("fl", "original")
+	query.Set("collapse", "urlkey")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cdxEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying wayback CDX API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wayback CDX API returned status %d", resp.StatusCode)
+	}
+
+	// The CDX API returns a JSON array of rows, the first of which is the
+	// column header (here just ["original"]).
+	var rows [][]string
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("decoding wayback CDX response: %w", err)
+	}
+
+	var urls []string
+	for i, row := range rows {
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+		urls = append(urls, row[0])
+	}
+
+	return urls, nil
+}
+
+func filterAndRelativize(urls []string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+
+	for _, raw := range urls {
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Path == "" || parsed.Path == "/" {
+			continue
+		}
+
+		lower := strings.ToLower(parsed.Path)
+		if !matchesAnyKeyword(lower) {
+			continue
+		}
+
+		path := strings.TrimPrefix(parsed.Path, "/")
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func matchesAnyKeyword(path string) bool {
+	for _, keyword := range portalKeywords {
+		if strings.Contains(path, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheKey(subdomain string) string {
+	key := strings.ToLower(subdomain)
+	key = strings.TrimPrefix(key, "https://")
+	key = strings.TrimPrefix(key, "http://")
+	return strings.ReplaceAll(strings.Trim(key, "/"), "/", "_")
+}
+
+func (d *Discoverer) cachePath(host string) string {
+	return filepath.Join(d.CacheDir, host+".json")
+}
+
+type cacheEntry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Paths     []string  `json:"paths"`
+}
+
+func (d *Discoverer) readCache(host string) ([]string, bool) {
+	if d.TTL <= 0 {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(d.cachePath(host))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if time.Since(entry.FetchedAt) > d.TTL {
+		return nil, false
+	}
+
+	return entry.Paths, true
+}
+
+func (d *Discoverer) writeCache(host string, paths []string) error {
+	entry := cacheEntry{FetchedAt: time.Now(), Paths: paths}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(d.cachePath(host), data, 0o644)
+}
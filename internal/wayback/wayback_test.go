@@ -0,0 +1,25 @@
+package wayback
+
+import "testing"
+
+func TestFilterAndRelativize(t *testing.T) {
+	urls := []string{
+		"https://example.com/legacy-sso/",
+		"https://example.com/v1/oauth/callback",
+		"https://example.com/images/logo.png",
+		"https://example.com/",
+	}
+
+	paths := filterAndRelativize(urls)
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 filtered paths, got %d (%v)", len(paths), paths)
+	}
+}
+
+func TestCacheKeyStripsScheme(t *testing.T) {
+	got := cacheKey("https://Example.com/")
+	want := "example.com"
+	if got != want {
+		t.Errorf("cacheKey() = %q, want %q", got, want)
+	}
+}
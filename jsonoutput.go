@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/sherwoodchaser/portalfinder/internal/formparse"
+	"github.com/sherwoodchaser/portalfinder/internal/idptakeover"
+)
+
+// JSONRecord is the structured, pipeable representation of a CheckResult,
+// written one-per-line when -json/-oJ is used.
+type JSONRecord struct {
+	Input           string                `json:"input"`
+	ProbedURL       string                `json:"probed_url"`
+	FinalURL        string                `json:"final_url"`
+	StatusCode      int                   `json:"status_code"`
+	RedirectChain   []string              `json:"redirect_chain"`
+	DetectionMethod string                `json:"detection_method"`
+	MatchedKeyword  string                `json:"matched_keyword"`
+	HasForm         bool                  `json:"has_form"`
+	FormAction      string                `json:"form_action"`
+	FormFields      []formparse.Field     `json:"form_fields,omitempty"`
+	OAuthParams     map[string]string     `json:"oauth_params,omitempty"`
+	IdPTakeover     []idptakeover.Finding `json:"idp_takeover,omitempty"`
+	Technology      []string              `json:"technology"`
+	Timestamp       string                `json:"timestamp"`
+}
+
+// ToJSONRecord converts a CheckResult into its JSONL output shape.
+func (r *CheckResult) ToJSONRecord() JSONRecord {
+	record := JSONRecord{
+		Input:         r.Subdomain,
+		ProbedURL:     r.ProbedURL,
+		FinalURL:      r.FinalURL,
+		StatusCode:    r.StatusCode,
+		RedirectChain: r.RedirectChain,
+		HasForm:       r.HasForm,
+		FormAction:    r.FormAction,
+		IdPTakeover:   r.TakeoverFindings,
+		Timestamp:     r.Timestamp.Format(time.RFC3339),
+	}
+
+	if r.Form != nil {
+		record.FormFields = r.Form.Fields
+		record.OAuthParams = r.Form.OAuthParams
+	}
+
+	if len(r.Matches) > 0 {
+		m := r.Matches[0]
+		record.DetectionMethod = string(m.Category)
+		if m.Rule != "" {
+			record.DetectionMethod = m.Rule
+		}
+		record.MatchedKeyword = m.MatchedKeyword
+		record.Technology = m.Technologies
+	}
+
+	return record
+}
+
+// saveResultsToJSONFile writes one JSON object per line for each result in
+// results. When includeNegatives is false, only results where Found is true
+// are written.
+func saveResultsToJSONFile(results []*CheckResult, filePath string, includeNegatives bool) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, result := range results {
+		if !result.Found && !includeNegatives {
+			continue
+		}
+		if err := encoder.Encode(result.ToJSONRecord()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
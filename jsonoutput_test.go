@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sherwoodchaser/portalfinder/internal/fingerprint"
+	"github.com/sherwoodchaser/portalfinder/internal/formparse"
+)
+
+func TestToJSONRecordMapsMatchAndForm(t *testing.T) {
+	result := &CheckResult{
+		Subdomain:  "login.example.com",
+		ProbedURL:  "https://login.example.com/login",
+		FinalURL:   "https://login.example.com/login",
+		StatusCode: 200,
+		HasForm:    true,
+		FormAction: "https://tenant.auth0.com/login",
+		Form: &formparse.Form{
+			Fields:      []formparse.Field{{Name: "email", Type: "email"}},
+			OAuthParams: map[string]string{"client_id": "abc123"},
+		},
+		Matches: []fingerprint.Match{
+			{Rule: "auth0", Category: fingerprint.CategoryOAuth, Technologies: []string{"Auth0"}, MatchedKeyword: "auth0-lock"},
+		},
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	record := result.ToJSONRecord()
+
+	if record.Input != "login.example.com" {
+		t.Errorf("Input = %q, want login.example.com", record.Input)
+	}
+	if record.DetectionMethod != "auth0" {
+		t.Errorf("DetectionMethod = %q, want auth0", record.DetectionMethod)
+	}
+	if record.MatchedKeyword != "auth0-lock" {
+		t.Errorf("MatchedKeyword = %q, want auth0-lock", record.MatchedKeyword)
+	}
+	if len(record.Technology) != 1 || record.Technology[0] != "Auth0" {
+		t.Errorf("Technology = %v, want [Auth0]", record.Technology)
+	}
+	if len(record.FormFields) != 1 || record.FormFields[0].Name != "email" {
+		t.Errorf("FormFields = %v, want one email field", record.FormFields)
+	}
+	if record.OAuthParams["client_id"] != "abc123" {
+		t.Errorf("OAuthParams[client_id] = %q, want abc123", record.OAuthParams["client_id"])
+	}
+	if record.Timestamp != "2024-01-02T03:04:05Z" {
+		t.Errorf("Timestamp = %q, want 2024-01-02T03:04:05Z", record.Timestamp)
+	}
+}
+
+func TestToJSONRecordNoMatchLeavesDetectionEmpty(t *testing.T) {
+	result := &CheckResult{Subdomain: "plain.example.com", Timestamp: time.Now()}
+
+	record := result.ToJSONRecord()
+
+	if record.DetectionMethod != "" {
+		t.Errorf("DetectionMethod = %q, want empty", record.DetectionMethod)
+	}
+	if record.FormFields != nil {
+		t.Errorf("FormFields = %v, want nil when Form is nil", record.FormFields)
+	}
+}
+
+func TestSaveResultsToJSONFileFiltersNegatives(t *testing.T) {
+	results := []*CheckResult{
+		{Subdomain: "found.example.com", Found: true, Timestamp: time.Now()},
+		{Subdomain: "notfound.example.com", Found: false, Timestamp: time.Now()},
+	}
+
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	if err := saveResultsToJSONFile(results, path, false); err != nil {
+		t.Fatalf("saveResultsToJSONFile: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line with includeNegatives=false, got %d: %v", len(lines), lines)
+	}
+
+	if err := saveResultsToJSONFile(results, path, true); err != nil {
+		t.Fatalf("saveResultsToJSONFile: %v", err)
+	}
+
+	lines = readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines with includeNegatives=true, got %d: %v", len(lines), lines)
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scanning %s: %v", path, err)
+	}
+	return lines
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -12,18 +13,68 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/sherwoodchaser/portalfinder/internal/fingerprint"
+	"github.com/sherwoodchaser/portalfinder/internal/formparse"
+	"github.com/sherwoodchaser/portalfinder/internal/httpx"
+	"github.com/sherwoodchaser/portalfinder/internal/idptakeover"
+	"github.com/sherwoodchaser/portalfinder/internal/wayback"
 )
 
-var createAccountPaths = []string{
-	"login", "register", "signup", "signin", "create-account", "log-in", "sign-in", "sign-up", "authentication", "forgot-password", "reset-password",
+// headerList collects repeated -H flags into "Key: Value" pairs.
+type headerList []string
+
+func (h *headerList) String() string { return strings.Join(*h, ",") }
+func (h *headerList) Set(value string) error {
+	*h = append(*h, value)
+	return nil
 }
 
-var createAccountKeywords = []string{
-	"login", "register", "signup", "signin", "create account", "log in", "sign in", "sign up", "authentication", "forgot password", "reset password",
+// Confidence is how strongly a detection method implies a real account
+// portal: a fingerprint rule match (body keyword, header, or form action)
+// plus a form is High, a link/button plus a form is Medium, and a bare form
+// with neither is Low.
+type Confidence string
+
+const (
+	ConfidenceNone   Confidence = ""
+	ConfidenceLow    Confidence = "low"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceHigh   Confidence = "high"
+)
+
+func confidenceWeight(c Confidence) int {
+	switch c {
+	case ConfidenceHigh:
+		return 3
+	case ConfidenceMedium:
+		return 2
+	case ConfidenceLow:
+		return 1
+	default:
+		return 0
+	}
 }
 
-var httpClient = &http.Client{
-	Timeout: 10 * time.Second, // Set a timeout for each request
+// CheckResult is everything checkSubdomain learned about a single
+// subdomain, including which fingerprint rule(s), if any, matched.
+type CheckResult struct {
+	Subdomain        string
+	Found            bool
+	Confidence       Confidence
+	ProbedURL        string
+	FinalURL         string
+	RedirectURL      string
+	RedirectChain    []string
+	StatusCode       int
+	HasForm          bool
+	FormAction       string
+	Form             *formparse.Form
+	TakeoverFindings []idptakeover.Finding
+	Matches          []fingerprint.Match
+	Timestamp        time.Time
 }
 
 func main() {
@@ -33,7 +84,26 @@ func main() {
 	// Parse command-line flags
 	listFlag := flag.String("l", "", "Path to file containing subdomains (one per line)")
 	outputFlag := flag.String("o", "", "Path to save valid subdomains")
+	jsonOutputFlag := flag.String("json", "", "Path to save structured JSONL results (one JSON object per line)")
+	flag.StringVar(jsonOutputFlag, "oJ", "", "Alias for -json")
+	allFlag := flag.Bool("o-all", false, "When used with -json, also store negative (no portal found) results")
+	configFlag := flag.String("config", "", "Path to YAML fingerprint config (defaults to the built-in ruleset)")
 	verboseFlag := flag.Bool("verbose", false, "Show all results (both with and without account portals)")
+	threadsFlag := flag.Int("threads", 25, "Number of concurrent workers")
+	rateLimitFlag := flag.Float64("rate-limit", 0, "Max requests/sec across all workers (0 = unlimited)")
+	timeoutFlag := flag.Duration("timeout", 10*time.Second, "Per-request timeout")
+	retriesFlag := flag.Int("retries", 0, "Number of retries for failed/5xx requests")
+	proxyFlag := flag.String("proxy", "", "HTTP/SOCKS5 proxy URL to route requests through")
+	randomAgentFlag := flag.Bool("random-agent", false, "Send a random User-Agent with each request")
+	insecureFlag := flag.Bool("insecure", false, "Skip TLS certificate verification")
+	maxTimeFlag := flag.Duration("max-time", 0, "Global deadline for the whole run (0 = no deadline)")
+	waybackFlag := flag.Bool("wayback", false, "Discover additional candidate paths via the Wayback Machine CDX API")
+	waybackTTLFlag := flag.Duration("wayback-ttl", 24*time.Hour, "How long cached Wayback results stay fresh")
+	matchFilterFlag := flag.String("mf", "", "Match filter: only keep results whose probed/final/redirect URL, form action, matched keyword or technology matches this regex")
+	excludeFilterFlag := flag.String("ef", "", "Exclude filter: drop results whose probed/final/redirect URL, form action, matched keyword or technology matches this regex")
+	minConfidenceFlag := flag.String("min-confidence", "medium", "Minimum detection confidence to report: low, medium, or high")
+	var headers headerList
+	flag.Var(&headers, "H", "Custom header to send with every request (repeatable), format 'Key: Value'")
 	flag.Parse()
 
 	if *listFlag == "" {
@@ -41,6 +111,70 @@ func main() {
 		return
 	}
 
+	minConfidence, err := parseConfidence(*minConfidenceFlag)
+	if err != nil {
+		log.Fatalf("Error parsing -min-confidence: %v", err)
+	}
+
+	var matchFilter, excludeFilter *regexp.Regexp
+	if *matchFilterFlag != "" {
+		matchFilter, err = regexp.Compile(*matchFilterFlag)
+		if err != nil {
+			log.Fatalf("Error compiling -mf regex: %v", err)
+		}
+	}
+	if *excludeFilterFlag != "" {
+		excludeFilter, err = regexp.Compile(*excludeFilterFlag)
+		if err != nil {
+			log.Fatalf("Error compiling -ef regex: %v", err)
+		}
+	}
+
+	cfg := fingerprint.Default()
+	if *configFlag != "" {
+		loaded, err := fingerprint.LoadConfig(*configFlag)
+		if err != nil {
+			log.Fatalf("Error loading fingerprint config: %v", err)
+		}
+		cfg = loaded
+	}
+
+	client, err := httpx.Build(httpx.Config{
+		Timeout:         *timeoutFlag,
+		Retries:         *retriesFlag,
+		Proxy:           *proxyFlag,
+		Headers:         headers,
+		RandomUserAgent: *randomAgentFlag,
+		Insecure:        *insecureFlag,
+	})
+	if err != nil {
+		log.Fatalf("Error building HTTP client: %v", err)
+	}
+
+	var limiter *rate.Limiter
+	if *rateLimitFlag > 0 {
+		limiter = rate.NewLimiter(rate.Limit(*rateLimitFlag), 1)
+	}
+
+	var discoverer *wayback.Discoverer
+	if *waybackFlag {
+		cacheDir, err := wayback.DefaultCacheDir()
+		if err != nil {
+			log.Fatalf("Error resolving wayback cache dir: %v", err)
+		}
+		discoverer, err = wayback.NewDiscoverer(client, cacheDir, *waybackTTLFlag)
+		if err != nil {
+			log.Fatalf("Error initializing wayback discoverer: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	if *maxTimeFlag > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *maxTimeFlag)
+		defer cancel()
+	}
+
 	// Read subdomains from file
 	subdomains, err := readSubdomainsFromFile(*listFlag)
 	if err != nil {
@@ -49,34 +183,48 @@ func main() {
 	}
 
 	var validSubdomains []string
+	var allResults []*CheckResult
 	var wg sync.WaitGroup
 	indicatorDone := make(chan bool) // Channel to control animation
 	progressChan := make(chan int)   // Channel to update progress
-	var mu sync.Mutex               // Mutex to protect progress variable
+	var mu sync.Mutex                // Mutex to protect progress/result slices
 	progress := 0                    // To track the progress of subdomains being processed
 
 	// Start the animated indicator and progress tracking in separate goroutines
 	go animatedIndicator(indicatorDone, len(subdomains), progressChan)
 
-	// Iterate through each subdomain concurrently
-	for idx, subdomain := range subdomains {
+	// Feed subdomains to a bounded pool of worker goroutines so a large
+	// input list can't exhaust file descriptors.
+	jobs := make(chan string)
+	for i := 0; i < *threadsFlag; i++ {
 		wg.Add(1)
-		go func(idx int, subdomain string) {
+		go func() {
 			defer wg.Done()
-			if checkSubdomain(subdomain, *verboseFlag) {
-				validSubdomains = append(validSubdomains, subdomain)
+			for subdomain := range jobs {
+				result := checkSubdomain(ctx, subdomain, cfg, client, limiter, discoverer, minConfidence, matchFilter, excludeFilter, *verboseFlag)
+
+				mu.Lock()
+				if result.Found {
+					validSubdomains = append(validSubdomains, subdomain)
+				}
+				allResults = append(allResults, result)
+				progress++
+				mu.Unlock()
+
+				progressChan <- progress
 			}
-			// Update progress safely using mutex
-			mu.Lock()
-			progress++
-			mu.Unlock()
+		}()
+	}
 
-			// Send the updated progress to the channel
-			progressChan <- progress
-		}(idx, subdomain)
+	for _, subdomain := range subdomains {
+		select {
+		case jobs <- subdomain:
+		case <-ctx.Done():
+		}
 	}
+	close(jobs)
 
-	// Wait for all goroutines to complete
+	// Wait for all workers to complete
 	wg.Wait()
 
 	// Stop the animated indicator
@@ -90,6 +238,15 @@ func main() {
 		}
 		fmt.Printf("\nValid subdomains saved to %s\n", *outputFlag)
 	}
+
+	// Save structured JSONL results if specified
+	if *jsonOutputFlag != "" {
+		err := saveResultsToJSONFile(allResults, *jsonOutputFlag, *allFlag)
+		if err != nil {
+			log.Fatalf("Error saving JSON results: %v", err)
+		}
+		fmt.Printf("\nStructured results saved to %s\n", *jsonOutputFlag)
+	}
 }
 
 // Animated loading indicator with progress
@@ -111,19 +268,17 @@ func animatedIndicator(done chan bool, total int, progressChan chan int) {
 
 func printBanner() {
 	banner := `
-	‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó  ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó ‚Ėą‚Ėą‚ēó     ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚Ėą‚ēó   ‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó 
-	‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēź‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó‚ēö‚ēź‚ēź‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēĚ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĎ     ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēź‚ēź‚ēĚ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚Ėą‚Ėą‚ēó  ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēź‚ēź‚ēĚ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó
-	‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēĒ‚ēĚ‚Ėą‚Ėą‚ēĎ   ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēĒ‚ēĚ   ‚Ėą‚Ėą‚ēĎ   ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĎ     ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó  ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĒ‚Ėą‚Ėą‚ēó ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĎ  ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó  ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēĒ‚ēĚ
-	‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēź‚ēĚ ‚Ėą‚Ėą‚ēĎ   ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó   ‚Ėą‚Ėą‚ēĎ   ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĎ     ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēĚ  ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĎ‚ēö‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĎ  ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚ēĚ  ‚Ėą‚Ėą‚ēĒ‚ēź‚ēź‚Ėą‚Ėą‚ēó
-	‚Ėą‚Ėą‚ēĎ     ‚ēö‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēĒ‚ēĚ‚Ėą‚Ėą‚ēĎ  ‚Ėą‚Ėą‚ēĎ   ‚Ėą‚Ėą‚ēĎ   ‚Ėą‚Ėą‚ēĎ  ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĎ     ‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚ēĎ ‚ēö‚Ėą‚Ėą‚Ėą‚Ėą‚ēĎ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēĒ‚ēĚ‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚Ėą‚ēó‚Ėą‚Ėą‚ēĎ  ‚Ėą‚Ėą‚ēĎ
-	‚ēö‚ēź‚ēĚ      ‚ēö‚ēź‚ēź‚ēź‚ēź‚ēź‚ēĚ ‚ēö‚ēź‚ēĚ  ‚ēö‚ēź‚ēĚ   ‚ēö‚ēź‚ēĚ   ‚ēö‚ēź‚ēĚ  ‚ēö‚ēź‚ēĚ‚ēö‚ēź‚ēź‚ēź‚ēź‚ēź‚ēź‚ēĚ‚ēö‚ēź‚ēĚ     ‚ēö‚ēź‚ēĚ‚ēö‚ēź‚ēĚ  ‚ēö‚ēź‚ēź‚ēź‚ēĚ‚ēö‚ēź‚ēź‚ēź‚ēź‚ēź‚ēĚ ‚ēö‚ēź‚ēź‚ēź‚ēź‚ēź‚ēź‚ēĚ‚ēö‚ēź‚ēĚ  ‚ēö‚ēź‚ēĚ
+	███████╗  ██████╗ ██████╗ ████████╗ █████╗ ██╗     ██████╗██╗███╗   ██╗██████╗ ███████╗██████╗
+	██╔══██╗██╔═══██╗██╔══██╗╚══██╔══╝██╔══██╗██║     ██╔════╝██║████╗  ██║██╔══██╗██╔════╝██╔══██╗
+	███████╔╝██║   ██║██████╔╝   ██║   ███████║██║     ██████╗╚██║██╔██╗ ██║██║  ██║█████╗  ██████╔╝
+	██╔══██╔╝██║   ██║██╔══██╗   ██║   ██╔══██║██║     ██╔══╝  ██║██║╚██╗██║██║  ██║██╔══╝  ██╔══██╗
+	██║     ╚██████╔╝██║  ██║   ██║   ██║  ██║███████╗██║     ██║██║ ╚████║██████╔╝███████╗██║  ██║
+	╚═╝      ╚═════╝ ╚═╝  ╚═╝   ╚═╝   ╚═╝  ╚═╝╚══════╝╚═╝     ╚═╝╚═╝  ╚═══╝╚═════╝ ╚══════╝╚═╝  ╚═╝
  `
 	fmt.Printf("\033[3m\033[1;34m%s\033[0m", banner)
-	fmt.Printf("\t\t\033[1;32m‚≠ź PortalFinder - Account Portal Detection Tool ūüó°ÔłŹ | Built by Sherwood Chaser ūüĆü\033[0m\n\n")
+	fmt.Printf("\t\t\033[1;32m⭐ PortalFinder - Account Portal Detection Tool 🗡️ | Built by Sherwood Chaser 🌟\033[0m\n\n")
 }
 
-
-
 func readSubdomainsFromFile(filePath string) ([]string, error) {
 	var subdomains []string
 	file, err := os.Open(filePath)
@@ -144,30 +299,49 @@ func readSubdomainsFromFile(filePath string) ([]string, error) {
 	return subdomains, nil
 }
 
-func checkSubdomain(subdomain string, verbose bool) bool {
-	accountPortalFound := false
-	redirectURL := ""
-	detectionMethod := ""
-	matchedKeyword := ""
-	hasForm := false
+func checkSubdomain(ctx context.Context, subdomain string, cfg *fingerprint.Config, client *http.Client, limiter *rate.Limiter, discoverer *wayback.Discoverer, minConfidence Confidence, matchFilter, excludeFilter *regexp.Regexp, verbose bool) *CheckResult {
+	result := &CheckResult{Subdomain: subdomain, Timestamp: time.Now()}
+
+	paths := cfg.Paths()
+	if discoverer != nil {
+		historical, err := discoverer.Paths(ctx, subdomain)
+		if err != nil {
+			log.Printf("wayback discovery failed for %s: %v", subdomain, err)
+		} else {
+			paths = append(paths, historical...)
+		}
+	}
+
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				break
+			}
+		}
 
-	for _, path := range createAccountPaths {
 		url := fmt.Sprintf("%s/%s", subdomain, path)
-		resp, err := httpClient.Get(url)
+		resp, chain, err := fetchWithRedirects(ctx, client, url)
 		if err != nil {
 			continue
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		statusCode := resp.StatusCode
+		redirectURL := ""
+		if statusCode >= 300 && statusCode < 400 {
 			redirectURL = resp.Header.Get("Location")
 		}
 
-		if resp.StatusCode != http.StatusOK && !strings.HasPrefix(fmt.Sprint(resp.StatusCode), "3") {
+		if statusCode != http.StatusOK && (statusCode < 300 || statusCode >= 400) {
+			resp.Body.Close()
 			continue
 		}
 
 		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
 			continue
 		}
@@ -175,57 +349,157 @@ func checkSubdomain(subdomain string, verbose bool) bool {
 			continue
 		}
 
-		if matchedKeyword = findMatchingKeyword(string(body)); matchedKeyword != "" && containsForm(string(body)) {
-			accountPortalFound = true
-			detectionMethod = "Keywords in body with form"
-			hasForm = true
-			break
+		bodyStr := string(body)
+		hasForm := containsForm(bodyStr)
+
+		var form *formparse.Form
+		formAction := ""
+		if forms, err := formparse.Parse(bodyStr); err == nil && len(forms) > 0 {
+			form = &forms[0]
+			formAction = form.Action
+		}
+
+		matches := cfg.Evaluate(bodyStr, resp.Header, formAction)
+
+		var confidence Confidence
+		switch {
+		case len(matches) > 0 && hasForm:
+			confidence = ConfidenceHigh
+		case containsCreateAccountLinks(bodyStr, cfg) && hasForm:
+			confidence = ConfidenceMedium
+			matches = []fingerprint.Match{{Rule: "links-with-form", Category: fingerprint.CategoryGenericLogin}}
+		case hasForm:
+			confidence = ConfidenceLow
+			matches = []fingerprint.Match{{Rule: "form-only", Category: fingerprint.CategoryGenericLogin}}
+		default:
+			continue
 		}
 
-		if containsCreateAccountLinks(string(body)) && containsForm(string(body)) {
-			accountPortalFound = true
-			detectionMethod = "Links or buttons in body with form"
-			hasForm = true
+		// A later path with higher confidence can still upgrade an
+		// earlier low-confidence (form-only) hit.
+		if confidenceWeight(confidence) <= confidenceWeight(result.Confidence) {
+			continue
+		}
+
+		result.Confidence = confidence
+		result.ProbedURL = url
+		result.StatusCode = statusCode
+		result.RedirectChain = chain
+		result.RedirectURL = redirectURL
+		if resp.Request != nil && resp.Request.URL != nil {
+			result.FinalURL = resp.Request.URL.String()
+		}
+		result.HasForm = hasForm
+		result.Matches = matches
+		result.Form = form
+		result.FormAction = formAction
+		result.TakeoverFindings = idptakeover.Check(ctx, client, client.Timeout, bodyStr+" "+formAction)
+
+		if confidence == ConfidenceHigh {
 			break
 		}
 	}
 
+	result.Found = confidenceWeight(result.Confidence) >= confidenceWeight(minConfidence) && result.Confidence != ConfidenceNone
+	applyFilters(result, matchFilter, excludeFilter)
+
 	fmt.Print("\r")
 
-	if accountPortalFound && hasForm {
-		if redirectURL != "" {
-			fmt.Printf("\033[32m[v] Subdomain has account portal: %s\033[0m -> redirect to: %s [Detected via: %s, Keyword: %s]\n", subdomain, redirectURL, detectionMethod, matchedKeyword)
+	if result.Found {
+		if result.RedirectURL != "" {
+			fmt.Printf("\033[32m[v] Subdomain has account portal: %s\033[0m -> redirect to: %s [Detected via: %s]\n", subdomain, result.RedirectURL, describeMatches(result.Matches))
 		} else {
-			fmt.Printf("\033[32m[v] Subdomain has account portal: %s\033[0m [Detected via: %s, Keyword: %s]\n", subdomain, detectionMethod, matchedKeyword)
-		}
-		return true
-	} else {
-		if verbose {
-			fmt.Printf("\033[31m[x] No account portal found: %s\033[0m\n", subdomain)
+			fmt.Printf("\033[32m[v] Subdomain has account portal: %s\033[0m [Detected via: %s]\n", subdomain, describeMatches(result.Matches))
 		}
-		return false
+	} else if verbose {
+		fmt.Printf("\033[31m[x] No account portal found: %s\033[0m\n", subdomain)
 	}
+
+	return result
 }
 
-func findMatchingKeyword(body string) string {
-	for _, keyword := range createAccountKeywords {
-		if strings.Contains(strings.ToLower(body), keyword) {
-			return keyword
+// fetchWithRedirects performs a GET request, recording every URL the
+// request was redirected through so it can be reported in JSON output.
+func fetchWithRedirects(ctx context.Context, base *http.Client, url string) (*http.Response, []string, error) {
+	var chain []string
+
+	client := *base
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		chain = append(chain, req.URL.String())
+		if len(via) >= 10 {
+			return http.ErrUseLastResponse
 		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.Do(req)
+	return resp, chain, err
+}
+
+// parseConfidence validates a -min-confidence value.
+func parseConfidence(value string) (Confidence, error) {
+	switch Confidence(strings.ToLower(value)) {
+	case ConfidenceLow:
+		return ConfidenceLow, nil
+	case ConfidenceMedium:
+		return ConfidenceMedium, nil
+	case ConfidenceHigh:
+		return ConfidenceHigh, nil
+	default:
+		return "", fmt.Errorf("invalid confidence %q (want low, medium, or high)", value)
+	}
+}
+
+// applyFilters drops a result from being reported if it fails the match
+// filter or trips the exclude filter, checking the probed/final/redirect
+// URL, the form action, and the matched keyword/technologies (if any).
+func applyFilters(result *CheckResult, matchFilter, excludeFilter *regexp.Regexp) {
+	if !result.Found || (matchFilter == nil && excludeFilter == nil) {
+		return
+	}
+
+	keyword := ""
+	technologies := ""
+	if len(result.Matches) > 0 {
+		keyword = result.Matches[0].MatchedKeyword
+		technologies = strings.Join(result.Matches[0].Technologies, " ")
+	}
+	candidate := strings.Join([]string{result.ProbedURL, result.FinalURL, result.RedirectURL, result.FormAction, keyword, technologies}, " ")
+
+	if matchFilter != nil && !matchFilter.MatchString(candidate) {
+		result.Found = false
+	}
+	if excludeFilter != nil && excludeFilter.MatchString(candidate) {
+		result.Found = false
+	}
+}
+
+func describeMatches(matches []fingerprint.Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	m := matches[0]
+	if m.MatchedKeyword != "" {
+		return fmt.Sprintf("%s, Keyword: %s", m.Rule, m.MatchedKeyword)
 	}
-	return ""
+	return m.Rule
 }
 
-func containsCreateAccountLinks(body string) bool {
+func containsCreateAccountLinks(body string, cfg *fingerprint.Config) bool {
 	linkPattern := `<a[^>]*href="([^"]*)"[^>]*>(.*?)</a>|<button[^>]*>(.*?)</button>`
 	re := regexp.MustCompile(linkPattern)
 	matches := re.FindAllStringSubmatch(body, -1)
 
 	for _, match := range matches {
-		if len(match) > 1 && findMatchingKeyword(match[1]) != "" {
+		if len(match) > 1 && cfg.MatchesAny(match[1]) {
 			return true
 		}
-		if len(match) > 2 && findMatchingKeyword(match[2]) != "" {
+		if len(match) > 2 && cfg.MatchesAny(match[2]) {
 			return true
 		}
 	}
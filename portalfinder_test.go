@@ -0,0 +1,113 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/sherwoodchaser/portalfinder/internal/fingerprint"
+)
+
+func TestParseConfidence(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Confidence
+		wantErr bool
+	}{
+		{"low", ConfidenceLow, false},
+		{"MEDIUM", ConfidenceMedium, false},
+		{"High", ConfidenceHigh, false},
+		{"nonsense", "", true},
+		{"", "", true},
+	}
+
+	for _, c := range cases {
+		got, err := parseConfidence(c.input)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseConfidence(%q): expected error, got nil", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseConfidence(%q): unexpected error: %v", c.input, err)
+		}
+		if got != c.want {
+			t.Errorf("parseConfidence(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestApplyFiltersSkipsResultsAlreadyNotFound(t *testing.T) {
+	result := &CheckResult{Found: false, ProbedURL: "https://example.com/login"}
+	applyFilters(result, regexp.MustCompile("nomatch"), nil)
+
+	if result.Found {
+		t.Errorf("expected Found to stay false")
+	}
+}
+
+func TestApplyFiltersMatchFilterDrops(t *testing.T) {
+	result := &CheckResult{Found: true, ProbedURL: "https://example.com/login"}
+	applyFilters(result, regexp.MustCompile("okta"), nil)
+
+	if result.Found {
+		t.Errorf("expected Found=false when match filter doesn't match")
+	}
+}
+
+func TestApplyFiltersMatchFilterKeeps(t *testing.T) {
+	result := &CheckResult{Found: true, ProbedURL: "https://tenant.okta.com/login"}
+	applyFilters(result, regexp.MustCompile("okta"), nil)
+
+	if !result.Found {
+		t.Errorf("expected Found=true when match filter matches")
+	}
+}
+
+func TestApplyFiltersExcludeFilterDrops(t *testing.T) {
+	result := &CheckResult{Found: true, ProbedURL: "https://staging.example.com/login"}
+	applyFilters(result, nil, regexp.MustCompile("staging"))
+
+	if result.Found {
+		t.Errorf("expected Found=false when exclude filter matches")
+	}
+}
+
+func TestApplyFiltersChecksMatchedKeyword(t *testing.T) {
+	result := &CheckResult{
+		Found:     true,
+		ProbedURL: "https://example.com/login",
+		Matches:   []fingerprint.Match{{MatchedKeyword: "okta-sign-in"}},
+	}
+	applyFilters(result, regexp.MustCompile("okta-sign-in"), nil)
+
+	if !result.Found {
+		t.Errorf("expected Found=true when match filter matches the matched keyword")
+	}
+}
+
+func TestApplyFiltersExcludeFilterChecksFormAction(t *testing.T) {
+	result := &CheckResult{
+		Found:      true,
+		ProbedURL:  "https://login.example.com/login",
+		FormAction: "https://tenant.okta.com/login",
+	}
+	applyFilters(result, nil, regexp.MustCompile(`okta\.com|auth0\.com`))
+
+	if result.Found {
+		t.Errorf("expected Found=false when exclude filter matches the form action")
+	}
+}
+
+func TestApplyFiltersMatchFilterChecksTechnologies(t *testing.T) {
+	result := &CheckResult{
+		Found:     true,
+		ProbedURL: "https://login.example.com/login",
+		Matches:   []fingerprint.Match{{Technologies: []string{"Okta"}}},
+	}
+	applyFilters(result, regexp.MustCompile("Okta"), nil)
+
+	if !result.Found {
+		t.Errorf("expected Found=true when match filter matches a matched technology")
+	}
+}